@@ -0,0 +1,105 @@
+package kingpin
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Alias registers one or more alternative long names this flag can also be
+// given as, eg. --out as well as --output.
+func (f *FlagClause) Alias(names ...string) *FlagClause {
+	extras.addFlagAlias(f, names...)
+	return f
+}
+
+// Hidden excludes this flag from help output. It still parses normally.
+func (f *FlagClause) Hidden() *FlagClause {
+	extras.setFlagHidden(f)
+	return f
+}
+
+func flagHidden(flag *FlagClause) bool {
+	return extras.isFlagHidden(flag)
+}
+
+// Alias registers one or more alternative names this command can also be
+// invoked by. Aliases are not listed separately in help output.
+func (c *CmdClause) Alias(names ...string) *CmdClause {
+	extras.addCmdAlias(c, names...)
+	return c
+}
+
+// Hidden excludes this command from help output. It still parses normally.
+func (c *CmdClause) Hidden() *CmdClause {
+	extras.setCmdHidden(c)
+	return c
+}
+
+func cmdHidden(cmd *CmdClause) bool {
+	return extras.isCmdHidden(cmd)
+}
+
+// registerAliases wires up aliases registered via FlagClause.Alias and
+// CmdClause.Alias, adding them to the lookup maps that parsing consults
+// without duplicating entries in flagOrder/commandOrder, so aliases parse
+// but don't appear a second time in help output. It's an error for an
+// alias to collide with an existing flag/command name or with another
+// alias, since silently overwriting that map entry would otherwise break
+// the flag/command it belongs to with no diagnostic.
+func registerAliases(flags *flagGroup, cmds *cmdGroup) error {
+	for _, flag := range flags.flagOrder {
+		for _, alias := range extras.flagAliasesFor(flag) {
+			if existing, ok := flags.long[alias]; ok {
+				return fmt.Errorf("alias --%s for --%s collides with existing flag --%s", alias, flag.name, existing.name)
+			}
+			flags.long[alias] = flag
+		}
+	}
+	if cmds == nil {
+		return nil
+	}
+	for _, cmd := range cmds.commandOrder {
+		for _, alias := range extras.cmdAliasesFor(cmd) {
+			if existing, ok := cmds.commands[alias]; ok {
+				return fmt.Errorf("alias %q for command %q collides with existing command %q", alias, cmd.name, existing.name)
+			}
+			cmds.commands[alias] = cmd
+		}
+		if err := registerAliases(cmd.flagGroup, cmd.cmdGroup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FlagsByName sorts flags lexically by long name.
+type FlagsByName []*FlagClause
+
+func (f FlagsByName) Len() int      { return len(f) }
+func (f FlagsByName) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
+func (f FlagsByName) Less(i, j int) bool {
+	return f[i].name < f[j].name
+}
+
+// CommandsByName sorts commands lexically by name.
+type CommandsByName []*CmdClause
+
+func (c CommandsByName) Len() int      { return len(c) }
+func (c CommandsByName) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c CommandsByName) Less(i, j int) bool {
+	return c[i].name < c[j].name
+}
+
+// SortFlags reorders this application's top-level flags alphabetically for
+// help output.
+func (a *Application) SortFlags() *Application {
+	sort.Sort(FlagsByName(a.flagGroup.flagOrder))
+	return a
+}
+
+// SortCommands reorders this application's commands alphabetically for
+// help output.
+func (a *Application) SortCommands() *Application {
+	sort.Sort(CommandsByName(a.commandOrder))
+	return a
+}
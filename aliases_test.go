@@ -0,0 +1,93 @@
+package kingpin
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFlagAliasCollisionWithExistingFlagIsAnError(t *testing.T) {
+	app := New("test", "")
+	app.Flag("output", "where to write output").String()
+	app.Flag("out", "shorthand for output").Alias("output").String()
+
+	if _, err := app.Parse([]string{}); err == nil {
+		t.Fatal("Parse: expected an error for colliding alias, got nil")
+	}
+}
+
+func TestCommandAliasCollisionWithExistingCommandIsAnError(t *testing.T) {
+	app := New("test", "")
+	app.Command("register", "Register a new user.")
+	app.Command("post", "Post a message.").Alias("register")
+
+	if _, err := app.Parse([]string{}); err == nil {
+		t.Fatal("Parse: expected an error for colliding command alias, got nil")
+	}
+}
+
+func TestFlagAliasParsesAndIsOmittedFromHelp(t *testing.T) {
+	app := New("test", "")
+	app.Flag("output", "where to write output").Alias("out").String()
+
+	if _, err := app.Parse([]string{"--out", "file.txt"}); err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+
+	buf := &bytes.Buffer{}
+	app.Usage(buf)
+	// Match --out only as a whole word, so the real --output flag doesn't count.
+	if regexp.MustCompile(`--out\b`).MatchString(buf.String()) {
+		t.Errorf("usage unexpectedly lists alias --out as its own flag: %s", buf.String())
+	}
+}
+
+func TestHiddenFlagAndCommandAreOmittedFromHelp(t *testing.T) {
+	app := New("test", "")
+	app.Flag("secret", "internal use only").Hidden().String()
+	app.Command("internal-only", "Not for end users.").Hidden()
+
+	buf := &bytes.Buffer{}
+	app.Usage(buf)
+	out := buf.String()
+	if strings.Contains(out, "secret") || strings.Contains(out, "internal-only") {
+		t.Errorf("usage unexpectedly lists hidden flag/command: %s", out)
+	}
+}
+
+func TestSortFlagsOrdersAlphabetically(t *testing.T) {
+	app := New("test", "")
+	app.Flag("zebra", "").Bool()
+	app.Flag("apple", "").Bool()
+	app.SortFlags()
+
+	names := make([]string, len(app.flagGroup.flagOrder))
+	for i, flag := range app.flagGroup.flagOrder {
+		names[i] = flag.name
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Errorf("flagOrder = %v, not sorted alphabetically", names)
+			break
+		}
+	}
+}
+
+func TestSortCommandsOrdersAlphabetically(t *testing.T) {
+	app := New("test", "")
+	app.Command("zebra", "")
+	app.Command("apple", "")
+	app.SortCommands()
+
+	names := make([]string, len(app.commandOrder))
+	for i, cmd := range app.commandOrder {
+		names[i] = cmd.name
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Errorf("commandOrder = %v, not sorted alphabetically", names)
+			break
+		}
+	}
+}
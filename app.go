@@ -46,6 +46,26 @@ type Application struct {
 	commandHelp *string
 	Name        string
 	Help        string
+
+	configPath             string
+	configFlagName         string
+	configLoader           ConfigLoader
+	allowUnknownConfigKeys bool
+
+	versionString string
+
+	argsFromFile  bool
+	stdinSentinel string
+
+	parsing bool
+}
+
+// parseAbort is panicked by UsageErrorf/FatalIfError when called while
+// Parse is executing (eg. from a Dispatch callback), so that a command's
+// fatal error turns into a returned *ParseError instead of killing the
+// process outright.
+type parseAbort struct {
+	err *ParseError
 }
 
 // New creates a new Kingpin application instance.
@@ -62,39 +82,106 @@ func New(name, help string) *Application {
 }
 
 func (a *Application) onFlagHelp() error {
-	a.Usage(os.Stderr)
-	os.Exit(0)
-	return nil
+	return &ParseError{Kind: HelpRequested}
 }
 
 // Parse parses command-line arguments. It returns the selected command and an
 // error. The selected command will be a space separated subcommand, if
 // subcommands have been configured.
 func (a *Application) Parse(args []string) (command string, err error) {
+	a.parsing = true
+	defer func() { a.parsing = false }()
+	defer func() {
+		if r := recover(); r != nil {
+			pa, ok := r.(parseAbort)
+			if !ok {
+				panic(r)
+			}
+			command, err = "", pa.err
+		}
+	}()
+
 	if err := a.init(); err != nil {
 		return "", err
 	}
-	tokens := Tokenize(args)
+	tokens, err := TokenizeWithOptions(args, TokenizeOptions{ArgsFromFile: a.argsFromFile, StdinSentinel: a.stdinSentinel})
+	if err != nil {
+		return "", err
+	}
+	if err := a.loadConfigFile(tokens); err != nil {
+		return "", err
+	}
+	if err := a.applyEnvars(); err != nil {
+		return "", err
+	}
 	tokens, command, err = a.parse(tokens)
 	if err != nil {
 		return "", err
 	}
 
 	if len(tokens) == 1 {
-		return "", fmt.Errorf("unexpected argument '%s'", tokens)
+		return "", &ParseError{Kind: UsageError, Token: tokens[0].String(), message: fmt.Sprintf("unexpected argument '%s'", tokens)}
 	} else if len(tokens) > 0 {
-		return "", fmt.Errorf("unexpected arguments '%s'", tokens)
+		return "", &ParseError{Kind: UsageError, Token: tokens[0].String(), message: fmt.Sprintf("unexpected arguments '%s'", tokens)}
 	}
 
 	return command, err
 }
 
+// Run parses args and returns the process exit code, printing help,
+// version, or error output to the appropriate stream along the way. It
+// never calls os.Exit itself, so callers choose whether and how to exit, eg:
+//
+//   os.Exit(app.Run(os.Args[1:]))
+func (a *Application) Run(args []string) int {
+	_, err := a.Parse(args)
+	return a.exitCode(err)
+}
+
+// MustParse is a helper for use with Application.Parse that exits the
+// process if Parse returned an error, including a requested --help or
+// --version. It is typically used as:
+//
+//   command := app.MustParse(app.Parse(os.Args[1:]))
+func (a *Application) MustParse(command string, err error) string {
+	if err == nil {
+		return command
+	}
+	os.Exit(a.exitCode(err))
+	return ""
+}
+
+func (a *Application) exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if pe, ok := err.(*ParseError); ok {
+		switch pe.Kind {
+		case HelpRequested:
+			if pe.Token != "" {
+				a.CommandUsage(os.Stdout, pe.Token)
+			} else {
+				a.Usage(os.Stdout)
+			}
+			return 0
+		case VersionRequested:
+			fmt.Fprintln(os.Stdout, a.versionString)
+			return 0
+		case CompletionRequested:
+			a.GenerateCompletion(pe.Token, os.Stdout)
+			return 0
+		}
+	}
+	a.Errorf(os.Stderr, "%s", err)
+	a.Usage(os.Stderr)
+	return 1
+}
+
 // Version adds a --version flag for displaying the application version.
 func (a *Application) Version(version string) *Application {
+	a.versionString = version
 	a.Flag("version", "Show application version.").Dispatch(func() error {
-		fmt.Println(version)
-		os.Exit(0)
-		return nil
+		return &ParseError{Kind: VersionRequested}
 	}).Bool()
 	return a
 }
@@ -129,14 +216,18 @@ func (a *Application) init() error {
 			return err
 		}
 	}
+	if err := registerAliases(a.flagGroup, a.cmdGroup); err != nil {
+		return err
+	}
 	a.initialized = true
 	return nil
 }
 
 func (a *Application) onCommandHelp() error {
-	a.CommandUsage(os.Stderr, *a.commandHelp)
-	os.Exit(0)
-	return nil
+	if _, ok := a.commands[*a.commandHelp]; !ok {
+		return &ParseError{Kind: UnknownCommand, Token: *a.commandHelp, message: fmt.Sprintf("unknown command '%s'", *a.commandHelp)}
+	}
+	return &ParseError{Kind: HelpRequested, Token: *a.commandHelp}
 }
 
 func (a *Application) parse(tokens tokens) (tokens, string, error) {
@@ -146,7 +237,7 @@ func (a *Application) parse(tokens tokens) (tokens, string, error) {
 	var err error
 	tokens, err = a.flagGroup.parse(tokens, runHelp)
 	if err != nil {
-		return tokens, "", err
+		return tokens, "", classifyParseError(err)
 	}
 
 	selected := []string{}
@@ -157,7 +248,7 @@ func (a *Application) parse(tokens tokens) (tokens, string, error) {
 	} else if a.cmdGroup.have() {
 		selected, tokens, err = a.cmdGroup.parse(tokens)
 	}
-	return tokens, strings.Join(selected, " "), err
+	return tokens, strings.Join(selected, " "), classifyParseError(err)
 }
 
 // Errorf prints an error message to w.
@@ -165,22 +256,33 @@ func (a *Application) Errorf(w io.Writer, format string, args ...interface{}) {
 	fmt.Fprintf(w, a.Name+": error: "+format+"\n", args...)
 }
 
-// UsageErrorf prints an error message followed by usage information, then
-// exits with a non-zero status.
+// UsageErrorf reports a usage error. While Application.Parse is executing
+// (eg. called from a Dispatch callback), it aborts parsing and the error
+// is returned from Parse as a *ParseError rather than exiting, so commands
+// built with kingpin stay testable. Called at any other time, it prints
+// the error and usage to w and exits with a non-zero status.
 func (a *Application) UsageErrorf(w io.Writer, format string, args ...interface{}) {
+	if a.parsing {
+		panic(parseAbort{&ParseError{Kind: UsageError, message: fmt.Sprintf(format, args...)}})
+	}
 	a.Errorf(w, format, args...)
 	a.Usage(w)
 	os.Exit(1)
 }
 
-// FatalIfError prints an error and exits if err is not nil. The error is printed
-// with the given prefix.
+// FatalIfError reports err, if it is not nil, with the given prefix. While
+// Application.Parse is executing it aborts parsing and returns the error
+// from Parse instead of exiting; otherwise it prints to w and exits.
 func (a *Application) FatalIfError(w io.Writer, err error, prefix string) {
-	if err != nil {
-		if prefix != "" {
-			prefix += ": "
-		}
-		a.Errorf(w, prefix+"%s", err)
-		os.Exit(1)
+	if err == nil {
+		return
+	}
+	if prefix != "" {
+		prefix += ": "
 	}
+	if a.parsing {
+		panic(parseAbort{&ParseError{Kind: UsageError, message: fmt.Sprintf(prefix+"%s", err)}})
+	}
+	a.Errorf(w, prefix+"%s", err)
+	os.Exit(1)
 }
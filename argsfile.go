@@ -0,0 +1,99 @@
+package kingpin
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// TokenizeOptions controls the optional argument preprocessing performed
+// by TokenizeWithOptions, before the normal flag/arg tokenization rules in
+// Tokenize apply.
+type TokenizeOptions struct {
+	// ArgsFromFile expands an argument of the form "@path/to/file" into
+	// the contents of that file, one resulting argument per line. Blank
+	// lines are ignored and "#" starts a line comment.
+	ArgsFromFile bool
+	// StdinSentinel, if non-empty, causes an argument exactly equal to it
+	// to be replaced with whitespace-separated tokens read from os.Stdin.
+	StdinSentinel string
+}
+
+// TokenizeWithOptions is like Tokenize, but first expands "@file"
+// arguments and/or a stdin sentinel token according to opts. Expansion
+// happens once, up front, so the Tokens.Return/Next/Peek backtracking
+// semantics of the result are unchanged from a plain Tokenize call.
+func TokenizeWithOptions(args []string, opts TokenizeOptions) (Tokens, error) {
+	expanded, err := expandArgs(args, opts)
+	if err != nil {
+		return nil, err
+	}
+	return Tokenize(expanded), nil
+}
+
+func expandArgs(args []string, opts TokenizeOptions) ([]string, error) {
+	var out []string
+	for _, arg := range args {
+		switch {
+		case opts.StdinSentinel != "" && arg == opts.StdinSentinel:
+			tokens, err := readStdinTokens()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, tokens...)
+
+		case opts.ArgsFromFile && strings.HasPrefix(arg, "@"):
+			tokens, err := readArgsFile(arg[1:])
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, tokens...)
+
+		default:
+			out = append(out, arg)
+		}
+	}
+	return out, nil
+}
+
+func readArgsFile(path string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		tokens = append(tokens, line)
+	}
+	return tokens, nil
+}
+
+func readStdinTokens() ([]string, error) {
+	data, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// AllowArgsFromFile enables "@path/to/file" arguments: such an argument is
+// replaced with the file's contents, one resulting argument per non-blank,
+// non-comment line.
+func (a *Application) AllowArgsFromFile(allow bool) *Application {
+	a.argsFromFile = allow
+	return a
+}
+
+// AllowArgsFromStdin causes an argument exactly equal to sentinel (eg. "-")
+// to be replaced with whitespace-separated tokens read from os.Stdin.
+func (a *Application) AllowArgsFromStdin(sentinel string) *Application {
+	a.stdinSentinel = sentinel
+	return a
+}
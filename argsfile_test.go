@@ -0,0 +1,61 @@
+package kingpin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpandArgsFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "args.txt")
+	if err := ioutil.WriteFile(path, []byte("--foo\n# a comment\n\nbar\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	got, err := expandArgs([]string{"@" + path, "baz"}, TokenizeOptions{ArgsFromFile: true})
+	if err != nil {
+		t.Fatalf("expandArgs: %s", err)
+	}
+	want := []string{"--foo", "bar", "baz"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expandArgs = %v, want %v", got, want)
+	}
+}
+
+func TestExpandArgsFromFileDisabledLeavesArgUntouched(t *testing.T) {
+	got, err := expandArgs([]string{"@nonexistent"}, TokenizeOptions{})
+	if err != nil {
+		t.Fatalf("expandArgs: %s", err)
+	}
+	want := []string{"@nonexistent"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expandArgs = %v, want %v", got, want)
+	}
+}
+
+func TestExpandArgsStdinSentinel(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe: %s", err)
+	}
+	origStdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = origStdin }()
+
+	go func() {
+		w.Write([]byte("one two three"))
+		w.Close()
+	}()
+
+	got, err := expandArgs([]string{"before", "-", "after"}, TokenizeOptions{StdinSentinel: "-"})
+	if err != nil {
+		t.Fatalf("expandArgs: %s", err)
+	}
+	want := []string{"before", "one", "two", "three", "after"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expandArgs = %v, want %v", got, want)
+	}
+}
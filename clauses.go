@@ -0,0 +1,105 @@
+package kingpin
+
+import "sync"
+
+// clauseExtras holds out-of-band metadata attached to FlagClause/CmdClause
+// values — completion hints, env var fallbacks, aliases, and visibility —
+// that several independent features need without reaching into the clause
+// constructors themselves. It is a single mutex-guarded store rather than
+// one ad hoc global map per feature, so concurrent Application
+// construction and parsing (eg. from tests exercising Application.Run)
+// doesn't race.
+type clauseExtras struct {
+	mu sync.Mutex
+
+	flagHints   map[*FlagClause]*hintSpec
+	flagEnvars  map[*FlagClause]*envarSpec
+	flagAliases map[*FlagClause][]string
+	hiddenFlags map[*FlagClause]bool
+
+	cmdAliases map[*CmdClause][]string
+	hiddenCmds map[*CmdClause]bool
+}
+
+var extras = &clauseExtras{
+	flagHints:   map[*FlagClause]*hintSpec{},
+	flagEnvars:  map[*FlagClause]*envarSpec{},
+	flagAliases: map[*FlagClause][]string{},
+	hiddenFlags: map[*FlagClause]bool{},
+	cmdAliases:  map[*CmdClause][]string{},
+	hiddenCmds:  map[*CmdClause]bool{},
+}
+
+func (e *clauseExtras) setHint(f *FlagClause, spec *hintSpec) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flagHints[f] = spec
+}
+
+func (e *clauseExtras) hint(f *FlagClause) (*hintSpec, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	spec, ok := e.flagHints[f]
+	return spec, ok
+}
+
+func (e *clauseExtras) setEnvar(f *FlagClause, spec *envarSpec) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flagEnvars[f] = spec
+}
+
+func (e *clauseExtras) envar(f *FlagClause) (*envarSpec, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	spec, ok := e.flagEnvars[f]
+	return spec, ok
+}
+
+func (e *clauseExtras) addFlagAlias(f *FlagClause, names ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flagAliases[f] = append(e.flagAliases[f], names...)
+}
+
+func (e *clauseExtras) flagAliasesFor(f *FlagClause) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.flagAliases[f]
+}
+
+func (e *clauseExtras) setFlagHidden(f *FlagClause) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.hiddenFlags[f] = true
+}
+
+func (e *clauseExtras) isFlagHidden(f *FlagClause) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.hiddenFlags[f]
+}
+
+func (e *clauseExtras) addCmdAlias(c *CmdClause, names ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cmdAliases[c] = append(e.cmdAliases[c], names...)
+}
+
+func (e *clauseExtras) cmdAliasesFor(c *CmdClause) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cmdAliases[c]
+}
+
+func (e *clauseExtras) setCmdHidden(c *CmdClause) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.hiddenCmds[c] = true
+}
+
+func (e *clauseExtras) isCmdHidden(c *CmdClause) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.hiddenCmds[c]
+}
@@ -0,0 +1,160 @@
+package kingpin
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// hintSpec holds the completion values for a single flag, either a fixed
+// list or a function to compute them lazily.
+type hintSpec struct {
+	options []string
+	action  func() []string
+}
+
+// HintOptions registers a fixed list of completion values for this flag,
+// eg. the members of an enumerated choice.
+func (f *FlagClause) HintOptions(options ...string) *FlagClause {
+	extras.setHint(f, &hintSpec{options: options})
+	return f
+}
+
+// HintAction registers a function invoked at completion time to produce
+// this flag's completion values.
+func (f *FlagClause) HintAction(action func() []string) *FlagClause {
+	extras.setHint(f, &hintSpec{action: action})
+	return f
+}
+
+func flagHintValues(flag *FlagClause) []string {
+	spec, ok := extras.hint(flag)
+	if !ok {
+		return nil
+	}
+	if spec.action != nil {
+		return spec.action()
+	}
+	return spec.options
+}
+
+// EnableCompletion registers hidden --completion-bash and --completion-zsh
+// flags that print a completion script for the application and exit.
+func (a *Application) EnableCompletion() *Application {
+	a.Flag("completion-bash", "Output bash completion script.").Hidden().Dispatch(func() error {
+		return &ParseError{Kind: CompletionRequested, Token: "bash"}
+	}).Bool()
+	a.Flag("completion-zsh", "Output zsh completion script.").Hidden().Dispatch(func() error {
+		return &ParseError{Kind: CompletionRequested, Token: "zsh"}
+	}).Bool()
+	return a
+}
+
+// GenerateCompletion writes a completion script for shell ("bash" or
+// "zsh") to w.
+func (a *Application) GenerateCompletion(shell string, w io.Writer) error {
+	switch shell {
+	case "bash":
+		writeBashCompletion(a, w)
+		return nil
+	case "zsh":
+		writeZshCompletion(a, w)
+		return nil
+	default:
+		return fmt.Errorf("unsupported completion shell %q", shell)
+	}
+}
+
+// completionWords returns the flag and argument completion words for a
+// single command-like clause (the application itself or one of its
+// commands).
+func completionWords(flags *flagGroup, args *argGroup) []string {
+	var words []string
+	for _, flag := range flags.flagOrder {
+		if flagHidden(flag) {
+			continue
+		}
+		words = append(words, "--"+flag.name)
+		if flag.shorthand != 0 {
+			words = append(words, "-"+string(flag.shorthand))
+		}
+	}
+	for _, arg := range args.args {
+		if arg.name != "" {
+			words = append(words, "<"+arg.name+">")
+		}
+	}
+	return words
+}
+
+// valueWords returns the completion words for the value of flag. It returns
+// nil for a bool flag, which takes no value, and for a flag with no hints,
+// which falls back to filename completion.
+func valueWords(flag *FlagClause) []string {
+	if fb, ok := flag.value.(boolFlag); ok && fb.IsBoolFlag() {
+		return nil
+	}
+	return flagHintValues(flag)
+}
+
+func writeBashCompletion(a *Application, w io.Writer) {
+	fn := "_" + bashFuncName(a.Name)
+	fmt.Fprintf(w, "# bash completion for %s\n", a.Name)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintf(w, "  local cur prev cmds flags\n")
+	fmt.Fprintf(w, "  COMPREPLY=()\n")
+	fmt.Fprintf(w, "  cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	fmt.Fprintf(w, "  prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+
+	for _, flag := range a.flagGroup.flagOrder {
+		if flagHidden(flag) {
+			continue
+		}
+		if hints := valueWords(flag); hints != nil {
+			fmt.Fprintf(w, "  if [[ \"$prev\" == \"--%s\" ]]; then COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") ); return 0; fi\n", flag.name, strings.Join(hints, " "))
+		} else if fb, ok := flag.value.(boolFlag); !ok || !fb.IsBoolFlag() {
+			fmt.Fprintf(w, "  if [[ \"$prev\" == \"--%s\" ]]; then COMPREPLY=( $(compgen -f -- \"$cur\") ); return 0; fi\n", flag.name)
+		}
+	}
+
+	cmdNames := []string{}
+	for _, cmd := range a.commandOrder {
+		if cmdHidden(cmd) {
+			continue
+		}
+		cmdNames = append(cmdNames, cmd.name)
+	}
+	fmt.Fprintf(w, "\n  cmds=\"%s\"\n", strings.Join(cmdNames, " "))
+	fmt.Fprintf(w, "  flags=\"%s\"\n\n", strings.Join(completionWords(a.flagGroup, a.argGroup), " "))
+	fmt.Fprintf(w, "  case \"$prev\" in\n")
+	for _, cmd := range a.commandOrder {
+		if cmdHidden(cmd) {
+			continue
+		}
+		fmt.Fprintf(w, "    %s) COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") ); return 0 ;;\n",
+			cmd.name, strings.Join(completionWords(cmd.flagGroup, cmd.argGroup), " "))
+	}
+	fmt.Fprintf(w, "  esac\n\n")
+	fmt.Fprintf(w, "  if [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(w, "    COMPREPLY=( $(compgen -W \"$flags\" -- \"$cur\") )\n")
+	fmt.Fprintf(w, "  else\n")
+	fmt.Fprintf(w, "    COMPREPLY=( $(compgen -W \"$cmds\" -- \"$cur\") )\n")
+	fmt.Fprintf(w, "  fi\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "complete -F %s %s\n", fn, a.Name)
+}
+
+func writeZshCompletion(a *Application, w io.Writer) {
+	fmt.Fprintf(w, "#compdef %s\n", a.Name)
+	fmt.Fprintf(w, "autoload -Uz bashcompinit && bashcompinit\n")
+	writeBashCompletion(a, w)
+}
+
+func bashFuncName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,50 @@
+package kingpin
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEnableCompletionReturnsCompletionRequested(t *testing.T) {
+	app := New("test", "")
+	app.EnableCompletion()
+
+	_, err := app.Parse([]string{"--completion-bash"})
+	pe, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("Parse error = %#v, want *ParseError", err)
+	}
+	if pe.Kind != CompletionRequested {
+		t.Errorf("Kind = %v, want CompletionRequested", pe.Kind)
+	}
+	if pe.Token != "bash" {
+		t.Errorf("Token = %q, want \"bash\"", pe.Token)
+	}
+}
+
+func TestGenerateCompletionListsVisibleFlagsAndCommands(t *testing.T) {
+	app := New("test", "")
+	app.Flag("output", "where to write output").String()
+	app.Flag("secret", "internal use only").Hidden().String()
+	app.Command("post", "Post a message.")
+
+	buf := &bytes.Buffer{}
+	if err := app.GenerateCompletion("bash", buf); err != nil {
+		t.Fatalf("GenerateCompletion: %s", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "--output") || !strings.Contains(out, "post") {
+		t.Errorf("completion script missing expected tokens: %s", out)
+	}
+	if strings.Contains(out, "--secret") {
+		t.Errorf("completion script unexpectedly includes hidden flag: %s", out)
+	}
+}
+
+func TestGenerateCompletionUnsupportedShell(t *testing.T) {
+	app := New("test", "")
+	if err := app.GenerateCompletion("fish", &bytes.Buffer{}); err == nil {
+		t.Fatal("GenerateCompletion: expected an error for unsupported shell")
+	}
+}
@@ -0,0 +1,240 @@
+package kingpin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ConfigFormat selects the on-disk encoding used by a config file loaded
+// with Application.ConfigFile or Application.ConfigFlag.
+type ConfigFormat int
+
+// Supported config file formats.
+const (
+	YAMLConfig ConfigFormat = iota
+	JSONConfig
+	INIConfig
+)
+
+// ConfigLoader decodes a config file into a flat set of values keyed by
+// dotted flag name, eg. "debug" for a top-level flag or "post.channel" for
+// the "channel" flag of the "post" command.
+type ConfigLoader interface {
+	Load(r io.Reader) (map[string]string, error)
+}
+
+func loaderFor(format ConfigFormat) ConfigLoader {
+	switch format {
+	case JSONConfig:
+		return jsonConfigLoader{}
+	case INIConfig:
+		return iniConfigLoader{}
+	default:
+		return yamlConfigLoader{}
+	}
+}
+
+type jsonConfigLoader struct{}
+
+func (jsonConfigLoader) Load(r io.Reader) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	flattenConfigValue("", raw, out)
+	return out, nil
+}
+
+func flattenConfigValue(prefix string, v interface{}, out map[string]string) {
+	if m, ok := v.(map[string]interface{}); ok {
+		for k, sub := range m {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenConfigValue(key, sub, out)
+		}
+		return
+	}
+	switch t := v.(type) {
+	case string:
+		out[prefix] = t
+	case bool:
+		out[prefix] = strconv.FormatBool(t)
+	case float64:
+		out[prefix] = strconv.FormatFloat(t, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, len(t))
+		for i, elem := range t {
+			parts[i] = fmt.Sprintf("%v", elem)
+		}
+		out[prefix] = strings.Join(parts, ",")
+	default:
+		out[prefix] = fmt.Sprintf("%v", t)
+	}
+}
+
+// yamlConfigLoader understands the common subset of YAML needed for flag
+// defaults: two-space indented nested maps and scalar values. It does not
+// support lists, anchors, or multi-line scalars.
+type yamlConfigLoader struct{}
+
+func (yamlConfigLoader) Load(r io.Reader) (map[string]string, error) {
+	out := map[string]string{}
+	var stack []string
+	var indents []int
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		for len(indents) > 0 && indent <= indents[len(indents)-1] {
+			indents = indents[:len(indents)-1]
+			stack = stack[:len(stack)-1]
+		}
+		parts := strings.SplitN(trimmed, ":", 2)
+		key := strings.TrimSpace(parts[0])
+		full := append(append([]string{}, stack...), key)
+		if len(parts) == 1 || strings.TrimSpace(parts[1]) == "" {
+			stack = full
+			indents = append(indents, indent)
+			continue
+		}
+		out[strings.Join(full, ".")] = strings.Trim(strings.TrimSpace(parts[1]), `"'`)
+	}
+	return out, scanner.Err()
+}
+
+type iniConfigLoader struct{}
+
+func (iniConfigLoader) Load(r io.Reader) (map[string]string, error) {
+	out := map[string]string{}
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid ini line %q", line)
+		}
+		key := strings.TrimSpace(parts[0])
+		if section != "" {
+			key = section + "." + key
+		}
+		out[key] = strings.TrimSpace(parts[1])
+	}
+	return out, scanner.Err()
+}
+
+// ConfigFile instructs a to load flag defaults from the structured config
+// file at path before command-line arguments are parsed. Dotted keys such
+// as "post.channel" seed the "channel" flag of the "post" command; values
+// given on the command line always override values loaded this way.
+func (a *Application) ConfigFile(path string, format ConfigFormat) *Application {
+	a.configPath = path
+	a.configLoader = loaderFor(format)
+	return a
+}
+
+// ConfigFlag registers a --config flag whose value, if given, names the
+// config file to load in place of any path set via ConfigFile.
+func (a *Application) ConfigFlag(format ConfigFormat) *Application {
+	a.configLoader = loaderFor(format)
+	a.configFlagName = "config"
+	a.Flag(a.configFlagName, "Load flag defaults from a config file.").String()
+	return a
+}
+
+// AllowUnknownConfigKeys disables the default error raised when a config
+// file loaded via ConfigFile or ConfigFlag contains a key with no matching
+// flag.
+func (a *Application) AllowUnknownConfigKeys() *Application {
+	a.allowUnknownConfigKeys = true
+	return a
+}
+
+// loadConfigFile reads the configured config file, if any, and seeds
+// matching flags with its values. It is called before flagGroup.parse so
+// that CLI-supplied tokens still take precedence.
+func (a *Application) loadConfigFile(tokens Tokens) error {
+	path := a.configPath
+	if a.configFlagName != "" {
+		if v, ok := scanTokenValue(tokens, a.configFlagName); ok && v != "" {
+			path = v
+		}
+	}
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	values, err := a.configLoader.Load(f)
+	if err != nil {
+		return fmt.Errorf("invalid config file %s: %s", path, err)
+	}
+
+	for key, value := range values {
+		flag := a.flagGroup.long[key]
+		if flag == nil {
+			if i := strings.IndexByte(key, '.'); i >= 0 {
+				if cmd, ok := a.commands[key[:i]]; ok {
+					flag = cmd.flagGroup.long[key[i+1:]]
+				}
+			}
+		}
+		if flag == nil {
+			if a.allowUnknownConfigKeys {
+				continue
+			}
+			return fmt.Errorf("unknown config key %q", key)
+		}
+		if r, ok := flag.value.(envarResettable); ok {
+			r.Reset()
+		}
+		for _, part := range strings.Split(value, ",") {
+			if err := flag.value.Set(part); err != nil {
+				return fmt.Errorf("invalid value for %q: %s", key, err)
+			}
+		}
+		flag.required = false
+	}
+	return nil
+}
+
+// scanTokenValue looks ahead through tokens for a long flag named long and
+// returns the value that would be bound to it, without consuming any
+// tokens. This lets the config file path be discovered before the normal
+// flag parsing pass runs.
+func scanTokenValue(tokens Tokens, long string) (string, bool) {
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.Type == TokenLong && t.Value == long {
+			if i+1 < len(tokens) && tokens[i+1].Type == TokenArg {
+				return tokens[i+1].Value, true
+			}
+			return "", true
+		}
+	}
+	return "", false
+}
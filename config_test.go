@@ -0,0 +1,82 @@
+package kingpin
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONConfigLoaderFlattensNestedKeys(t *testing.T) {
+	loader := jsonConfigLoader{}
+	values, err := loader.Load(strings.NewReader(`{"debug": true, "post": {"channel": "general"}}`))
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if values["debug"] != "true" {
+		t.Errorf("debug = %q, want \"true\"", values["debug"])
+	}
+	if values["post.channel"] != "general" {
+		t.Errorf("post.channel = %q, want \"general\"", values["post.channel"])
+	}
+}
+
+func TestJSONConfigLoaderFlattensLists(t *testing.T) {
+	loader := jsonConfigLoader{}
+	values, err := loader.Load(strings.NewReader(`{"tags": ["a", "b", "c"]}`))
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if values["tags"] != "a,b,c" {
+		t.Errorf("tags = %q, want \"a,b,c\"", values["tags"])
+	}
+}
+
+func TestYAMLConfigLoaderHandlesNesting(t *testing.T) {
+	loader := yamlConfigLoader{}
+	values, err := loader.Load(strings.NewReader("debug: true\npost:\n  channel: general\n"))
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if values["debug"] != "true" {
+		t.Errorf("debug = %q, want \"true\"", values["debug"])
+	}
+	if values["post.channel"] != "general" {
+		t.Errorf("post.channel = %q, want \"general\"", values["post.channel"])
+	}
+}
+
+func TestConfigFileSplitsListValueForCumulativeFlag(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(`{"tags": ["a", "b", "c"]}`), 0644); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	app := New("test", "")
+	tags := app.Flag("tags", "tags to apply").Strings()
+	app.ConfigFile(path, JSONConfig)
+
+	if _, err := app.Parse([]string{}); err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	want := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(*tags, want) {
+		t.Errorf("tags = %v, want %v", *tags, want)
+	}
+}
+
+func TestINIConfigLoaderHandlesSections(t *testing.T) {
+	loader := iniConfigLoader{}
+	values, err := loader.Load(strings.NewReader("debug=true\n[post]\nchannel=general\n"))
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if values["debug"] != "true" {
+		t.Errorf("debug = %q, want \"true\"", values["debug"])
+	}
+	if values["post.channel"] != "general" {
+		t.Errorf("post.channel = %q, want \"general\"", values["post.channel"])
+	}
+}
@@ -0,0 +1,87 @@
+package kingpin
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envarSpec holds the environment variable fallback configured for a flag.
+type envarSpec struct {
+	name      string
+	separator string
+}
+
+// Envar tells the flag to fall back to the value of the named environment
+// variable when it is not given on the command line. Precedence is:
+// CLI token, then environment variable, then any config file value, then
+// Default(). A flag satisfied by its environment variable is treated as
+// given for the purposes of Required().
+func (f *FlagClause) Envar(name string) *FlagClause {
+	extras.setEnvar(f, &envarSpec{name: name, separator: ","})
+	return f
+}
+
+// EnvarSeparator sets the separator used to split a slice-typed flag's
+// environment variable value. It has no effect unless Envar has already
+// been called. The default separator is ",".
+func (f *FlagClause) EnvarSeparator(separator string) *FlagClause {
+	if spec, ok := extras.envar(f); ok {
+		spec.separator = separator
+	}
+	return f
+}
+
+func flagEnvarName(flag *FlagClause) (string, bool) {
+	spec, ok := extras.envar(flag)
+	if !ok {
+		return "", false
+	}
+	return spec.name, true
+}
+
+// envarResettable is implemented by cumulative (slice-typed) flag values
+// that can clear a previously-set value, eg. one already seeded by
+// Default() or a config file. Without it, an env var's values would be
+// appended to rather than replace such a value.
+type envarResettable interface {
+	Reset()
+}
+
+// applyEnvars seeds any flag with a registered Envar from its environment
+// variable, if set. It runs after config file values are applied and
+// before CLI tokens are parsed, so that CLI tokens still win.
+func (a *Application) applyEnvars() error {
+	if err := applyEnvarsForFlags(a.flagGroup); err != nil {
+		return err
+	}
+	for _, cmd := range a.commands {
+		if err := applyEnvarsForFlags(cmd.flagGroup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyEnvarsForFlags(flags *flagGroup) error {
+	for _, flag := range flags.flagOrder {
+		spec, ok := extras.envar(flag)
+		if !ok {
+			continue
+		}
+		value, ok := os.LookupEnv(spec.name)
+		if !ok || value == "" {
+			continue
+		}
+		if r, ok := flag.value.(envarResettable); ok {
+			r.Reset()
+		}
+		for _, part := range strings.Split(value, spec.separator) {
+			if err := flag.value.Set(part); err != nil {
+				return fmt.Errorf("invalid value for $%s: %s", spec.name, err)
+			}
+		}
+		flag.required = false
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package kingpin
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestEnvarSeedsFlagWhenNotGivenOnCommandLine(t *testing.T) {
+	const name = "KINGPIN_TEST_ENVAR"
+	os.Setenv(name, "general")
+	defer os.Unsetenv(name)
+
+	app := New("test", "")
+	channel := app.Flag("channel", "channel to post to").Envar(name).Required().String()
+
+	if _, err := app.Parse([]string{}); err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if *channel != "general" {
+		t.Errorf("channel = %q, want \"general\"", *channel)
+	}
+}
+
+func TestEnvarDoesNotOverrideCommandLineToken(t *testing.T) {
+	const name = "KINGPIN_TEST_ENVAR"
+	os.Setenv(name, "general")
+	defer os.Unsetenv(name)
+
+	app := New("test", "")
+	channel := app.Flag("channel", "channel to post to").Envar(name).String()
+
+	if _, err := app.Parse([]string{"--channel", "random"}); err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	if *channel != "random" {
+		t.Errorf("channel = %q, want \"random\"", *channel)
+	}
+}
+
+func TestEnvarResetsCumulativeValueInsteadOfAppending(t *testing.T) {
+	const name = "KINGPIN_TEST_ENVAR_LIST"
+	os.Setenv(name, "x,y")
+	defer os.Unsetenv(name)
+
+	app := New("test", "")
+	tags := app.Flag("tags", "tags to apply").Envar(name).Default("a", "b").Strings()
+
+	if _, err := app.Parse([]string{}); err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	want := []string{"x", "y"}
+	if !reflect.DeepEqual(*tags, want) {
+		t.Errorf("tags = %v, want %v (env should replace, not append to, the default)", *tags, want)
+	}
+}
+
+func TestEnvarSeparatorOverridesDefaultComma(t *testing.T) {
+	const name = "KINGPIN_TEST_ENVAR_SEP"
+	os.Setenv(name, "x:y:z")
+	defer os.Unsetenv(name)
+
+	app := New("test", "")
+	tags := app.Flag("tags", "tags to apply").Envar(name).EnvarSeparator(":").Strings()
+
+	if _, err := app.Parse([]string{}); err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+	want := []string{"x", "y", "z"}
+	if !reflect.DeepEqual(*tags, want) {
+		t.Errorf("tags = %v, want %v", *tags, want)
+	}
+}
@@ -0,0 +1,85 @@
+package kingpin
+
+import "strings"
+
+// ParseErrorKind classifies the sentinel errors that Application.Parse can
+// return so that callers can distinguish, eg, a requested --help from an
+// actual usage mistake.
+type ParseErrorKind int
+
+// Kinds of ParseError.
+const (
+	UsageError ParseErrorKind = iota
+	HelpRequested
+	VersionRequested
+	CompletionRequested
+	// RequiredMissing is returned when a required flag or argument was not
+	// given.
+	RequiredMissing
+	// UnknownCommand is returned when the selected command doesn't match
+	// any registered command.
+	UnknownCommand
+	// ValueError is returned when a flag or argument's value couldn't be
+	// converted to its underlying type.
+	ValueError
+)
+
+// ParseError is returned by Application.Parse for both genuine parse
+// failures and for the built-in --help/--version/help requests, so that
+// library consumers can drive the parser without their process
+// terminating. Token holds the offending or requested token, eg. the
+// command name for a "help <command>" request.
+type ParseError struct {
+	Kind    ParseErrorKind
+	Token   string
+	message string
+}
+
+func (e *ParseError) Error() string {
+	if e.message != "" {
+		return e.message
+	}
+	switch e.Kind {
+	case HelpRequested:
+		return "help requested"
+	case VersionRequested:
+		return "version requested"
+	case CompletionRequested:
+		return "completion requested"
+	case RequiredMissing:
+		return "required value missing"
+	case UnknownCommand:
+		return "unknown command"
+	case ValueError:
+		return "invalid value"
+	default:
+		return "usage error"
+	}
+}
+
+// classifyParseError wraps a plain error returned by flagGroup.parse,
+// argGroup.parse, or cmdGroup.parse into a *ParseError carrying the most
+// specific Kind its message indicates, so that callers driving the parser
+// programmatically can distinguish a missing required flag/argument, an
+// unrecognised command, and a bad value from a generic usage error. Errors
+// that are already a *ParseError (eg. from a Dispatch callback) pass
+// through unchanged.
+func classifyParseError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*ParseError); ok {
+		return err
+	}
+	msg := err.Error()
+	kind := UsageError
+	switch {
+	case strings.Contains(msg, "required"):
+		kind = RequiredMissing
+	case strings.Contains(msg, "expected command") || strings.Contains(msg, "unknown command"):
+		kind = UnknownCommand
+	case strings.Contains(msg, "invalid") || strings.Contains(msg, "unable to parse") || strings.Contains(msg, "can't set"):
+		kind = ValueError
+	}
+	return &ParseError{Kind: kind, message: msg}
+}
@@ -0,0 +1,64 @@
+package kingpin
+
+import "testing"
+
+func TestHelpRequestedReturnsParseErrorInsteadOfExiting(t *testing.T) {
+	app := New("test", "")
+
+	_, err := app.Parse([]string{"--help"})
+	pe, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("Parse error = %#v, want *ParseError", err)
+	}
+	if pe.Kind != HelpRequested {
+		t.Errorf("Kind = %v, want HelpRequested", pe.Kind)
+	}
+}
+
+func TestDispatchFatalIfErrorAbortsParseWithUsageError(t *testing.T) {
+	app := New("test", "")
+	app.Flag("name", "name of user").Dispatch(func() error {
+		app.FatalIfError(nil, errBoom, "checking name")
+		return nil
+	}).String()
+
+	_, err := app.Parse([]string{"--name", "bob"})
+	pe, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("Parse error = %#v, want *ParseError", err)
+	}
+	if pe.Kind != UsageError {
+		t.Errorf("Kind = %v, want UsageError", pe.Kind)
+	}
+}
+
+func TestClassifyParseErrorPassesThroughParseError(t *testing.T) {
+	pe := &ParseError{Kind: VersionRequested}
+	if got := classifyParseError(pe); got != error(pe) {
+		t.Errorf("classifyParseError = %#v, want the original *ParseError unchanged", got)
+	}
+}
+
+func TestClassifyParseErrorClassifiesByMessage(t *testing.T) {
+	cases := []struct {
+		msg  string
+		kind ParseErrorKind
+	}{
+		{"required flag --name not provided", RequiredMissing},
+		{"expected command but got \"bogus\"", UnknownCommand},
+		{"invalid value for --age", ValueError},
+		{"something else entirely", UsageError},
+	}
+	for _, c := range cases {
+		got := classifyParseError(errString(c.msg)).(*ParseError)
+		if got.Kind != c.kind {
+			t.Errorf("classifyParseError(%q).Kind = %v, want %v", c.msg, got.Kind, c.kind)
+		}
+	}
+}
+
+type errString string
+
+func (e errString) Error() string { return string(e) }
+
+var errBoom = errString("boom")
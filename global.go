@@ -14,10 +14,7 @@ var (
 )
 
 func Parse() string {
-	selected, err := CommandLine.Parse(os.Args[1:])
-	if err != nil {
-		Fatalf("%s", err)
-	}
+	selected := CommandLine.MustParse(CommandLine.Parse(os.Args[1:]))
 	if selected == "" && len(CommandLine.commands) > 0 {
 		Usage()
 		os.Exit(0)
@@ -25,23 +22,32 @@ func Parse() string {
 	return selected
 }
 
-// Fatalf prints an error message to stderr and exits.
+// Fatalf reports a fatal error. While CommandLine.Parse is executing (eg.
+// called from a Dispatch callback), it aborts parsing and the error is
+// returned from Parse as a *ParseError rather than exiting. Called at any
+// other time, it prints to stderr and exits.
 func Fatalf(format string, args ...interface{}) {
+	if CommandLine.parsing {
+		panic(parseAbort{&ParseError{Kind: UsageError, message: fmt.Sprintf(format, args...)}})
+	}
 	fmt.Fprintf(os.Stderr, "error: "+format+"\n", args...)
 	os.Exit(1)
 }
 
-// FatalIfError prints an error and exits, if err is not nil. The error is printed
-// with the given prefix.
+// FatalIfError reports err, if it is not nil, with the given prefix. See
+// Fatalf for its behavior while CommandLine.Parse is executing.
 func FatalIfError(err error, prefix string) {
 	if err != nil {
 		Fatalf(prefix+": %s", err)
 	}
 }
 
-// UsageErrorf prints an error message followed by usage information, then
-// exits with a non-zero status.
+// UsageErrorf reports a usage error followed by usage information. See
+// Fatalf for its behavior while CommandLine.Parse is executing.
 func UsageErrorf(format string, args ...interface{}) {
+	if CommandLine.parsing {
+		panic(parseAbort{&ParseError{Kind: UsageError, message: fmt.Sprintf(format, args...)}})
+	}
 	fmt.Fprintf(os.Stderr, "error: "+format+"\n", args...)
 	Usage()
 	os.Exit(1)
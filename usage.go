@@ -49,6 +49,9 @@ func (c *Application) writeHelp(width int, w io.Writer) {
 
 func (c *Application) helpCommands(width int, w io.Writer) {
 	for _, cmd := range c.commandOrder {
+		if cmdHidden(cmd) {
+			continue
+		}
 		fmt.Fprintf(w, "  %s\n", formatArgsAndFlags(cmd.name, cmd.argGroup, cmd.flagGroup))
 		buf := bytes.NewBuffer(nil)
 		doc.ToText(buf, cmd.help, "", "", width-4)
@@ -68,6 +71,9 @@ func (f *flagGroup) writeHelp(indent, width int, w io.Writer) {
 	fmt.Fprintf(w, "\nFlags:\n")
 	l := 0
 	for _, flag := range f.long {
+		if flagHidden(flag) {
+			continue
+		}
 		if fl := len(formatFlag(flag)); fl > l {
 			l = fl
 		}
@@ -78,6 +84,9 @@ func (f *flagGroup) writeHelp(indent, width int, w io.Writer) {
 	indentStr := strings.Repeat(" ", l)
 
 	for _, flag := range f.flagOrder {
+		if flagHidden(flag) {
+			continue
+		}
 		prefix := fmt.Sprintf("  %-*s", l-2, formatFlag(flag))
 		buf := bytes.NewBuffer(nil)
 		doc.ToText(buf, flag.help, "", "", width-l)
@@ -91,6 +100,9 @@ func (f *flagGroup) writeHelp(indent, width int, w io.Writer) {
 
 func (f *flagGroup) gatherFlagSummary() (out []string) {
 	for _, flag := range f.flagOrder {
+		if flagHidden(flag) {
+			continue
+		}
 		if flag.required {
 			fb, ok := flag.value.(boolFlag)
 			if ok && fb.IsBoolFlag() {
@@ -100,7 +112,7 @@ func (f *flagGroup) gatherFlagSummary() (out []string) {
 			}
 		}
 	}
-	if len(f.long) != len(out) {
+	if len(f.flagOrder) != len(out) {
 		out = append(out, "[<flags>]")
 	}
 	return
@@ -174,5 +186,8 @@ func formatFlag(flag *FlagClause) string {
 	if !ok || !fb.IsBoolFlag() {
 		flagString += fmt.Sprintf("=%s", flag.formatPlaceHolder())
 	}
+	if envar, ok := flagEnvarName(flag); ok {
+		flagString += fmt.Sprintf(" ($%s)", envar)
+	}
 	return flagString
 }